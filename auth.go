@@ -0,0 +1,80 @@
+package sshx
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// WithPassword adds password authentication, tried after publickey and
+// keyboard-interactive, the same order ssh(1) uses, and only if the server
+// offers it. fn is called once per attempt; pass TerminalPassword for a
+// ready-made /dev/tty prompt.
+func WithPassword(fn func() (string, error)) Option {
+	return func(o *options) { o.password = fn }
+}
+
+// WithKeyboardInteractive adds keyboard-interactive authentication, tried
+// after publickey and only if the server offers it. Pass
+// TerminalKeyboardInteractive for a ready-made /dev/tty prompt.
+func WithKeyboardInteractive(fn ssh.KeyboardInteractiveChallenge) Option {
+	return func(o *options) { o.keyboardInteractive = fn }
+}
+
+// TerminalPassword prompts for a password on /dev/tty with echo disabled.
+// It's the natural default to pass to WithPassword.
+func TerminalPassword() (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("ssh: could not open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprint(tty, "Password: ")
+	password, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return "", fmt.Errorf("ssh: could not read password: %w", err)
+	}
+	return string(password), nil
+}
+
+// TerminalKeyboardInteractive answers each question on /dev/tty, hiding the
+// answer whenever the server asked for it not to be echoed. It's the
+// natural default to pass to WithKeyboardInteractive.
+func TerminalKeyboardInteractive(name, instruction string, questions []string, echos []bool) (answers []string, err error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+
+	if instruction != "" {
+		fmt.Fprintln(tty, instruction)
+	}
+
+	reader := bufio.NewReader(tty)
+	answers = make([]string, len(questions))
+	for i, question := range questions {
+		fmt.Fprint(tty, question)
+		if i < len(echos) && !echos[i] {
+			answer, err := term.ReadPassword(int(tty.Fd()))
+			fmt.Fprintln(tty)
+			if err != nil {
+				return nil, fmt.Errorf("ssh: could not read answer: %w", err)
+			}
+			answers[i] = string(answer)
+			continue
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("ssh: could not read answer: %w", err)
+		}
+		answers[i] = strings.TrimRight(line, "\r\n")
+	}
+	return answers, nil
+}
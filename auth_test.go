@@ -0,0 +1,43 @@
+package sshx_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/matthewmueller/sshx"
+)
+
+func TestConfigureAuthMethodOrder(t *testing.T) {
+	is := is.New(t)
+	// Keep the agent lookup out of it so the Auth slice only reflects what
+	// WithKeyboardInteractive/WithPassword add.
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	keyboardInteractive := func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+		return nil, nil
+	}
+	password := func() (string, error) { return "", nil }
+
+	config := sshx.Configure("user", "host",
+		sshx.WithIdentitiesOnly(),
+		sshx.WithPassword(password),
+		sshx.WithKeyboardInteractive(keyboardInteractive),
+	)
+
+	// ssh(1) tries keyboard-interactive before password - Configure must
+	// preserve that order regardless of the order the options were passed in.
+	// AuthMethod doesn't expose its RFC 4252 method name outside the ssh
+	// package, so distinguish the two by their concrete callback type instead.
+	is.Equal(len(config.Auth), 2)
+	is.Equal(fmt.Sprintf("%T", config.Auth[0]), "ssh.KeyboardInteractiveChallenge")
+	is.Equal(fmt.Sprintf("%T", config.Auth[1]), "ssh.passwordCallback")
+}
+
+func TestConfigureAuthMethodsAreOptional(t *testing.T) {
+	is := is.New(t)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	config := sshx.Configure("user", "host", sshx.WithIdentitiesOnly())
+	is.Equal(len(config.Auth), 0)
+}
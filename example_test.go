@@ -1,9 +1,11 @@
 package sshx_test
 
 import (
+	"net"
 	"time"
 
 	"github.com/matthewmueller/sshx"
+	"golang.org/x/crypto/ssh"
 )
 
 func ExampleDial() {
@@ -21,11 +23,13 @@ func ExampleDial() {
 	// sshx
 }
 
-func ExampleDialConfig() {
+func ExampleConfigure() {
+	// Configure is for advanced use cases that need to tweak the
+	// *ssh.ClientConfig before dialing - here, a custom TCP connect timeout.
 	cfg := sshx.Configure("vagrant", "127.0.0.1:2222")
 	cfg.Timeout = time.Second
-	// Dial a server
-	client, err := sshx.DialConfig("127.0.0.1:2222", cfg)
+
+	client, err := ssh.Dial("tcp", "127.0.0.1:2222", cfg)
 	if err != nil {
 		panic(err)
 	}
@@ -38,6 +42,31 @@ func ExampleDialConfig() {
 	// sshx
 }
 
+func ExampleListenLocal() {
+	// Dial the bastion that can reach the Postgres server
+	client, err := sshx.Dial("vagrant", "127.0.0.1:2222")
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	// Forward a local port to the remote Postgres port, the way ssh(1)'s
+	// -p 2222 -L 15432:127.0.0.1:5432 vagrant@127.0.0.1 would
+	listener, err := sshx.ListenLocal(client, "127.0.0.1:15432", "127.0.0.1:5432")
+	if err != nil {
+		panic(err)
+	}
+	defer listener.Close()
+
+	// Any Postgres client pointed at 127.0.0.1:15432 now talks to the
+	// remote server through the tunnel
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+}
+
 func ExampleTest() {
 	// Dial a server
 	signer, err := sshx.Test("vagrant", "127.0.0.1:2222")
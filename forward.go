@@ -0,0 +1,245 @@
+package sshx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Handler receives the error from a single forwarded connection, the way
+// ssh(1) would log it. A nil Handler discards them.
+type Handler func(err error)
+
+func (h Handler) call(err error) {
+	if err != nil && h != nil {
+		h(err)
+	}
+}
+
+// Forwarder builds local, remote, and dynamic (-L/-R/-D) tunnels on top of
+// an *ssh.Client. The zero value discards per-connection errors; set
+// Handler to observe them.
+type Forwarder struct {
+	Handler Handler
+}
+
+// ListenLocal implements ssh(1)'s -L: it listens on localAddr and, for
+// every accepted connection, dials remoteAddr through client and copies
+// bytes between the two until either side closes.
+func ListenLocal(client *ssh.Client, localAddr, remoteAddr string) (net.Listener, error) {
+	return Forwarder{}.ListenLocal(client, localAddr, remoteAddr)
+}
+
+// ListenLocal is like the package-level ListenLocal, but reports
+// per-connection errors to f.Handler.
+func (f Forwarder) ListenLocal(client *ssh.Client, localAddr, remoteAddr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not listen on %s: %w", localAddr, err)
+	}
+	go f.acceptLoop(listener, func(local net.Conn) {
+		remote, err := client.Dial("tcp", remoteAddr)
+		if err != nil {
+			local.Close()
+			f.Handler.call(fmt.Errorf("ssh: could not dial %s: %w", remoteAddr, err))
+			return
+		}
+		f.pipe(local, remote)
+	})
+	return listener, nil
+}
+
+// ListenRemote implements ssh(1)'s -R: it asks the remote host to listen on
+// remoteAddr and, for every connection it accepts, dials localAddr and
+// copies bytes between the two.
+func ListenRemote(client *ssh.Client, remoteAddr, localAddr string) (net.Listener, error) {
+	return Forwarder{}.ListenRemote(client, remoteAddr, localAddr)
+}
+
+// ListenRemote is like the package-level ListenRemote, but reports
+// per-connection errors to f.Handler.
+func (f Forwarder) ListenRemote(client *ssh.Client, remoteAddr, localAddr string) (net.Listener, error) {
+	listener, err := client.Listen("tcp", remoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not listen on remote %s: %w", remoteAddr, err)
+	}
+	go f.acceptLoop(listener, func(remote net.Conn) {
+		local, err := net.Dial("tcp", localAddr)
+		if err != nil {
+			remote.Close()
+			f.Handler.call(fmt.Errorf("ssh: could not dial %s: %w", localAddr, err))
+			return
+		}
+		f.pipe(remote, local)
+	})
+	return listener, nil
+}
+
+// ListenSOCKS implements ssh(1)'s -D: it listens on localAddr as a minimal
+// SOCKS5 CONNECT-only proxy, dialing every requested destination through
+// client.
+func ListenSOCKS(client *ssh.Client, localAddr string) (net.Listener, error) {
+	return Forwarder{}.ListenSOCKS(client, localAddr)
+}
+
+// ListenSOCKS is like the package-level ListenSOCKS, but reports
+// per-connection errors to f.Handler.
+func (f Forwarder) ListenSOCKS(client *ssh.Client, localAddr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not listen on %s: %w", localAddr, err)
+	}
+	go f.acceptLoop(listener, func(conn net.Conn) {
+		f.serveSOCKS(conn, client.Dial)
+	})
+	return listener, nil
+}
+
+func (f Forwarder) acceptLoop(listener net.Listener, serve func(net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		go serve(conn)
+	}
+}
+
+// pipe copies bytes bidirectionally between a and b. Once either direction
+// ends, both are closed, which unblocks the other direction's io.Copy in
+// turn; pipe waits for both to actually finish before returning.
+func (f Forwarder) pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	relay := func(dst, src net.Conn) {
+		_, err := io.Copy(dst, src)
+		a.Close()
+		b.Close()
+		if err != nil && !errors.Is(err, net.ErrClosed) {
+			f.Handler.call(err)
+		}
+		done <- struct{}{}
+	}
+	go relay(a, b)
+	go relay(b, a)
+	<-done
+	<-done
+}
+
+const (
+	socksVersion5          = 0x05
+	socksCmdConnect        = 0x01
+	socksAtypIPv4          = 0x01
+	socksAtypDomainName    = 0x03
+	socksAtypIPv6          = 0x04
+	socksRepSucceeded      = 0x00
+	socksRepGeneralFailure = 0x01
+)
+
+// serveSOCKS speaks just enough SOCKS5 to handle an unauthenticated CONNECT
+// request, then hands the connection off to pipe using dial as the
+// upstream dialer.
+func (f Forwarder) serveSOCKS(conn net.Conn, dial func(network, addr string) (net.Conn, error)) {
+	if err := socksHandshake(conn); err != nil {
+		conn.Close()
+		f.Handler.call(fmt.Errorf("ssh: socks handshake: %w", err))
+		return
+	}
+	addr, err := socksReadRequest(conn)
+	if err != nil {
+		conn.Close()
+		f.Handler.call(fmt.Errorf("ssh: socks request: %w", err))
+		return
+	}
+	upstream, err := dial("tcp", addr)
+	if err != nil {
+		writeSOCKSReply(conn, socksRepGeneralFailure)
+		conn.Close()
+		f.Handler.call(fmt.Errorf("ssh: socks dial %s: %w", addr, err))
+		return
+	}
+	if err := writeSOCKSReply(conn, socksRepSucceeded); err != nil {
+		conn.Close()
+		upstream.Close()
+		f.Handler.call(fmt.Errorf("ssh: socks reply: %w", err))
+		return
+	}
+	f.pipe(conn, upstream)
+}
+
+// socksHandshake reads the client's method selection and replies that no
+// authentication is required, the only method this server supports.
+func socksHandshake(conn net.Conn) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != socksVersion5 {
+		return fmt.Errorf("unsupported socks version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return err
+	}
+	_, err := conn.Write([]byte{socksVersion5, 0x00})
+	return err
+}
+
+// socksReadRequest reads a CONNECT request and returns its destination as a
+// host:port string.
+func socksReadRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if header[0] != socksVersion5 {
+		return "", fmt.Errorf("unsupported socks version %d", header[0])
+	}
+	if header[1] != socksCmdConnect {
+		return "", fmt.Errorf("unsupported socks command %d", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case socksAtypIPv4:
+		addr := make([]byte, 4)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	case socksAtypDomainName:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	case socksAtypIPv6:
+		addr := make([]byte, 16)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", err
+		}
+		host = net.IP(addr).String()
+	default:
+		return "", fmt.Errorf("unsupported socks address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1]))), nil
+}
+
+// writeSOCKSReply writes a minimal SOCKS5 reply. The bound address is
+// always reported as 0.0.0.0:0 since callers never rely on it.
+func writeSOCKSReply(conn net.Conn, rep byte) error {
+	_, err := conn.Write([]byte{socksVersion5, rep, 0x00, socksAtypIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -0,0 +1,34 @@
+package sshx
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPipeClosesBothSidesWhenOneEnds(t *testing.T) {
+	is := is.New(t)
+
+	a, b := net.Pipe()
+	c, d := net.Pipe()
+
+	go Forwarder{}.pipe(b, c)
+
+	_, err := d.Write([]byte("hello"))
+	is.NoErr(err)
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(a, buf)
+	is.NoErr(err)
+	is.Equal(string(buf), "hello")
+
+	// Closing d ends only the c->b direction of the relay. pipe must then
+	// close b as well, so a observes EOF instead of hanging forever on a
+	// side that nothing will ever write to again.
+	is.NoErr(d.Close())
+
+	_, err = a.Read(buf)
+	is.Equal(err, io.EOF)
+}
@@ -0,0 +1,71 @@
+package sshx
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// HostKeyPrompt decides whether to trust a host key that isn't already in
+// known_hosts. It's called with the same arguments ssh(1) would show the
+// user: the hostname being dialed, the remote address, and the offered key.
+type HostKeyPrompt func(hostname string, remote net.Addr, key ssh.PublicKey) (accept bool, err error)
+
+// WithHostKeyPrompt overrides the prompt used when StrictHostKeyChecking is
+// "ask" (the default in interactive terminals). The zero value uses
+// TerminalHostKeyPrompt.
+func WithHostKeyPrompt(prompt HostKeyPrompt) Option {
+	return func(o *options) { o.hostKeyPrompt = prompt }
+}
+
+// WithStrictHostKeyChecking overrides how unknown host keys are handled,
+// mirroring ssh_config's StrictHostKeyChecking: "yes" rejects them, "ask"
+// prompts via HostKeyPrompt, and "no"/"accept-new" trusts and remembers
+// them silently.
+func WithStrictHostKeyChecking(mode string) Option {
+	return func(o *options) { o.strictHostKeyChecking = mode }
+}
+
+// TerminalHostKeyPrompt prompts on /dev/tty with the standard OpenSSH
+// message, accepting "yes", "no", or a pasted fingerprint. It's the default
+// HostKeyPrompt used when StrictHostKeyChecking is "ask".
+func TerminalHostKeyPrompt(hostname string, remote net.Addr, key ssh.PublicKey) (accept bool, err error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, fmt.Errorf("ssh: could not open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+
+	fingerprint := ssh.FingerprintSHA256(key)
+	fmt.Fprintf(tty, "The authenticity of host '%s (%s)' can't be established.\n", hostname, remote)
+	fmt.Fprintf(tty, "%s key fingerprint is %s.\n", keyTypeName(key.Type()), fingerprint)
+	fmt.Fprint(tty, "Are you sure you want to continue connecting (yes/no/[fingerprint])? ")
+
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("ssh: could not read answer: %w", err)
+	}
+	answer = strings.TrimSpace(answer)
+	return strings.EqualFold(answer, "yes") || answer == fingerprint, nil
+}
+
+// keyTypeName renders a key algorithm the way OpenSSH prints it, e.g.
+// "ssh-ed25519" -> "ED25519".
+func keyTypeName(algo string) string {
+	switch algo {
+	case ssh.KeyAlgoED25519:
+		return "ED25519"
+	case ssh.KeyAlgoRSA:
+		return "RSA"
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		return "ECDSA"
+	case ssh.KeyAlgoDSA:
+		return "DSA"
+	default:
+		return strings.ToUpper(strings.TrimPrefix(algo, "ssh-"))
+	}
+}
@@ -0,0 +1,24 @@
+package sshx
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestKeyTypeName(t *testing.T) {
+	is := is.New(t)
+	is.Equal(keyTypeName(ssh.KeyAlgoED25519), "ED25519")
+	is.Equal(keyTypeName(ssh.KeyAlgoRSA), "RSA")
+	is.Equal(keyTypeName(ssh.KeyAlgoECDSA256), "ECDSA")
+	is.Equal(keyTypeName(ssh.KeyAlgoDSA), "DSA")
+	is.Equal(keyTypeName("ssh-weird"), "WEIRD")
+}
+
+func TestDefaultStrictHostKeyCheckingNonTerminal(t *testing.T) {
+	is := is.New(t)
+	// go test's stdin isn't a terminal, so this should fall back to
+	// accept-new rather than prompting for input that will never come.
+	is.Equal(defaultStrictHostKeyChecking(), "accept-new")
+}
@@ -0,0 +1,135 @@
+package sshx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// PassphraseFunc prompts the user for the passphrase protecting the private
+// key at path, returning it to decrypt the key.
+type PassphraseFunc func(path string) (string, error)
+
+// maxPassphraseAttempts mirrors ssh(1)'s default NumberOfPasswordPrompts.
+const maxPassphraseAttempts = 3
+
+// identityCache holds signers already loaded in this process, keyed by
+// absolute path, so the same key is never decrypted or prompted for twice
+// across multiple Dial calls.
+var identityCache sync.Map // map[string]ssh.Signer
+
+// LoadIdentity loads the private key at path. If this process already
+// loaded it, the cached signer is returned. Unencrypted keys are parsed
+// directly. Encrypted keys first look for path+".pub", and if a running
+// ssh-agent already holds the matching private key, its agent-backed
+// signer is returned with no passphrase prompt at all; otherwise prompt
+// (default TerminalPassphrase) is called, up to three times in total while
+// the passphrase is incorrect.
+func LoadIdentity(path string, prompt PassphraseFunc) (ssh.Signer, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not resolve identity path %q: %w", path, err)
+	}
+	if cached, ok := identityCache.Load(abs); ok {
+		return cached.(ssh.Signer), nil
+	}
+
+	raw, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer, err := ssh.ParsePrivateKey(raw); err == nil {
+		identityCache.Store(abs, signer)
+		return signer, nil
+	} else if !isPassphraseMissing(err) {
+		return nil, fmt.Errorf("ssh: could not parse identity %q: %w", path, err)
+	}
+
+	if signer, err := signerFromAgent(abs); err == nil {
+		identityCache.Store(abs, signer)
+		return signer, nil
+	}
+
+	if prompt == nil {
+		prompt = TerminalPassphrase
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxPassphraseAttempts; attempt++ {
+		passphrase, err := prompt(path)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKeyWithPassphrase(raw, []byte(passphrase))
+		if err == nil {
+			identityCache.Store(abs, signer)
+			return signer, nil
+		}
+		if !errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, fmt.Errorf("ssh: could not parse identity %q: %w", path, err)
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("ssh: incorrect passphrase for identity %q: %w", path, lastErr)
+}
+
+func isPassphraseMissing(err error) bool {
+	var missing *ssh.PassphraseMissingError
+	return errors.As(err, &missing)
+}
+
+// signerFromAgent looks for path+".pub" and, if the running ssh-agent holds
+// the matching private key, returns an agent-backed signer for it. This
+// lets a key already unlocked in the agent satisfy an encrypted identity
+// file without ever prompting for its passphrase.
+func signerFromAgent(path string) (ssh.Signer, error) {
+	pub, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		return nil, err
+	}
+	wantKey, _, _, _, err := ssh.ParseAuthorizedKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	sshAgent, err := loadAgent()
+	if err != nil {
+		return nil, err
+	}
+	signers, err := sshAgent.Signers()
+	if err != nil {
+		return nil, err
+	}
+	for _, signer := range signers {
+		if bytes.Equal(signer.PublicKey().Marshal(), wantKey.Marshal()) {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("ssh: agent does not hold %q", path+".pub")
+}
+
+// TerminalPassphrase prompts for a private key's passphrase on /dev/tty
+// with echo disabled. It's the default PassphraseFunc used by LoadIdentity.
+func TerminalPassphrase(path string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("ssh: could not open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "Enter passphrase for key '%s': ", path)
+	passphrase, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return "", fmt.Errorf("ssh: could not read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
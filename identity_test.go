@@ -0,0 +1,85 @@
+package sshx_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/matthewmueller/sshx"
+	"golang.org/x/crypto/ssh"
+)
+
+func writeIdentity(t *testing.T, passphrase string) string {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadIdentityCachesByPath(t *testing.T) {
+	is := is.New(t)
+	path := writeIdentity(t, "")
+
+	first, err := sshx.LoadIdentity(path, nil)
+	is.NoErr(err)
+	second, err := sshx.LoadIdentity(path, nil)
+	is.NoErr(err)
+	// A fresh parse would produce a distinct Signer each call; equal here
+	// means the second call was served from identityCache.
+	is.True(first == second)
+}
+
+func TestLoadIdentityRetriesPassphrase(t *testing.T) {
+	is := is.New(t)
+	path := writeIdentity(t, "open sesame")
+
+	attempts := 0
+	prompt := func(string) (string, error) {
+		attempts++
+		if attempts < 2 {
+			return "wrong", nil
+		}
+		return "open sesame", nil
+	}
+
+	signer, err := sshx.LoadIdentity(path, prompt)
+	is.NoErr(err)
+	is.True(signer != nil)
+	is.Equal(attempts, 2)
+}
+
+func TestLoadIdentityGivesUpAfterMaxAttempts(t *testing.T) {
+	is := is.New(t)
+	path := writeIdentity(t, "open sesame")
+
+	attempts := 0
+	prompt := func(string) (string, error) {
+		attempts++
+		return "wrong", nil
+	}
+
+	_, err := sshx.LoadIdentity(path, prompt)
+	is.True(err != nil)
+	is.Equal(attempts, 3)
+}
@@ -0,0 +1,80 @@
+package sshx
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// DialJump dials host through a chain of bastions, the way ssh(1)'s
+// ProxyJump does: it dials jumps[0] directly, then for every later hop
+// (and finally user@host) tunnels through the previous hop's connection
+// with Dial. Closing the returned client tears down every intermediate
+// connection in the chain, in reverse order.
+//
+// Each hop is resolved through Split, so a hop given as a bare ssh_config
+// alias dials the HostName/Port it maps to, not the alias string itself -
+// the ordinary case for a ProxyJump directive naming a Host block.
+func DialJump(user, host string, jumps []string, signers ...ssh.Signer) (*ssh.Client, error) {
+	if len(jumps) == 0 {
+		return Dial(user, host, signers...)
+	}
+
+	firstUser, firstHost, err := Split(jumps[0])
+	if err != nil {
+		return nil, fmt.Errorf("ssh: invalid bastion %q: %w", jumps[0], err)
+	}
+	current, err := Dial(firstUser, firstHost, signers...)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not dial bastion %q: %w", jumps[0], err)
+	}
+	chain := []*ssh.Client{current}
+
+	closeChain := func() {
+		for i := len(chain) - 1; i >= 0; i-- {
+			chain[i].Close()
+		}
+	}
+
+	finalHop := host
+	if user != "" {
+		finalHop = fmt.Sprintf("%s@%s", user, host)
+	}
+	hops := append(append([]string{}, jumps[1:]...), finalHop)
+	for _, hop := range hops {
+		hopUser, hopHost, err := Split(hop)
+		if err != nil {
+			closeChain()
+			return nil, fmt.Errorf("ssh: invalid hop %q: %w", hop, err)
+		}
+		hopHost = formatHost(hopHost)
+
+		conn, err := current.Dial("tcp", hopHost)
+		if err != nil {
+			closeChain()
+			return nil, fmt.Errorf("ssh: could not reach %q through the bastion chain: %w", hopHost, err)
+		}
+
+		config := Configure(hopUser, hopHost, signerOptions(signers)...)
+		clientConn, newChans, reqs, err := ssh.NewClientConn(conn, hopHost, config)
+		if err != nil {
+			conn.Close()
+			closeChain()
+			return nil, fmt.Errorf("ssh: could not handshake with %q: %w", hopHost, err)
+		}
+
+		current = ssh.NewClient(clientConn, newChans, reqs)
+		chain = append(chain, current)
+	}
+
+	final := chain[len(chain)-1]
+	intermediates := chain[:len(chain)-1]
+	go func() {
+		final.Wait()
+		for i := len(intermediates) - 1; i >= 0; i-- {
+			intermediates[i].Close()
+		}
+	}()
+
+	return final, nil
+}
@@ -0,0 +1,37 @@
+package sshx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/matthewmueller/sshx"
+)
+
+func TestDialJumpHopResolvesSSHConfigAlias(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	sshDir := filepath.Join(dir, ".ssh")
+	is.NoErr(os.MkdirAll(sshDir, 0700))
+
+	config := `
+Host bastion
+  HostName 10.0.0.9
+  Port 2200
+  User jump
+`
+	is.NoErr(os.WriteFile(filepath.Join(sshDir, "config"), []byte(config), 0600))
+	t.Setenv("HOME", dir)
+
+	// DialJump dials each hop (including the bastion named by a ProxyJump
+	// directive) by calling Split on it. Split must resolve "bastion" to the
+	// HostName/Port above instead of dialing the literal alias - this is
+	// the same resolution TestSplitResolvesHostNameAndPort checks directly,
+	// exercised here against the exact string shape DialJump passes in.
+	user, host, err := sshx.Split("bastion")
+	is.NoErr(err)
+	is.Equal(user, "jump")
+	is.Equal(host, "10.0.0.9:2200")
+}
@@ -0,0 +1,171 @@
+package sshx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// DialOption customizes the behavior of DialContext itself, as opposed to
+// Option, which customizes the *ssh.ClientConfig it builds.
+type DialOption func(*dialOptions)
+
+type dialOptions struct {
+	retry *RetryPolicy
+}
+
+// RetryPolicy configures exponential backoff retries for transient dial
+// failures — the standard "wait for a freshly-provisioned VM to come up"
+// use case.
+type RetryPolicy struct {
+	// Initial is the delay before the first retry. Defaults to 1s.
+	Initial time.Duration
+	// Max caps the delay between retries. Defaults to 30s.
+	Max time.Duration
+	// Factor multiplies the delay after each retry. Defaults to 2.
+	Factor float64
+	// Jitter randomizes up to this fraction of each delay, to avoid
+	// thundering herds when many callers retry in lockstep. Defaults to 0.1.
+	Jitter float64
+	// Deadline bounds the total time spent retrying, independent of ctx.
+	// Zero means no additional deadline.
+	Deadline time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Initial <= 0 {
+		p.Initial = time.Second
+	}
+	if p.Max <= 0 {
+		p.Max = 30 * time.Second
+	}
+	if p.Factor <= 0 {
+		p.Factor = 2
+	}
+	if p.Jitter <= 0 {
+		p.Jitter = 0.1
+	}
+	return p
+}
+
+// WithRetry retries transient dial failures (connection refused, no route
+// to host, i/o timeout, a handshake cut short) with exponential backoff,
+// stopping immediately on non-transient errors such as a rejected host key
+// or failed auth.
+func WithRetry(policy RetryPolicy) DialOption {
+	policy = policy.withDefaults()
+	return func(o *dialOptions) { o.retry = &policy }
+}
+
+// DialContext is like Dial, but accepts DialOptions such as WithRetry and
+// stops as soon as ctx is done.
+func DialContext(ctx context.Context, user, host string, opts ...DialOption) (*ssh.Client, error) {
+	o := dialOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.retry == nil {
+		return dialOnce(ctx, user, host)
+	}
+	return dialWithRetry(ctx, user, host, *o.retry)
+}
+
+func dialOnce(ctx context.Context, user, host string) (*ssh.Client, error) {
+	type result struct {
+		client *ssh.Client
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		client, err := Dial(user, host)
+		done <- result{client, err}
+	}()
+	select {
+	case <-ctx.Done():
+		// The dial above is still running in the background and may still
+		// succeed after we've given up on it. Close any client it produces
+		// instead of leaking the connection.
+		go func() {
+			if r := <-done; r.client != nil {
+				r.client.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.client, r.err
+	}
+}
+
+func dialWithRetry(ctx context.Context, user, host string, policy RetryPolicy) (*ssh.Client, error) {
+	if policy.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Deadline)
+		defer cancel()
+	}
+
+	delay := policy.Initial
+	for {
+		client, err := dialOnce(ctx, user, host)
+		if err == nil {
+			return client, nil
+		}
+		if !isTransient(err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(jitteredDelay(delay, policy.Jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("ssh: giving up dialing %s@%s: %w", user, host, err)
+		case <-timer.C:
+		}
+
+		delay = nextDelay(delay, policy)
+	}
+}
+
+// jitteredDelay adds up to jitter's fraction of random jitter to delay, to
+// avoid thundering herds when many callers retry in lockstep.
+func jitteredDelay(delay time.Duration, jitter float64) time.Duration {
+	return delay + time.Duration(rand.Float64()*jitter*float64(delay))
+}
+
+// nextDelay grows delay by policy.Factor, capped at policy.Max.
+func nextDelay(delay time.Duration, policy RetryPolicy) time.Duration {
+	delay = time.Duration(float64(delay) * policy.Factor)
+	if delay > policy.Max {
+		delay = policy.Max
+	}
+	return delay
+}
+
+// isTransient reports whether err looks like the kind of failure that
+// clears up once a freshly-booted host finishes starting its sshd:
+// connection refused, no route to host, an i/o timeout, or the connection
+// being cut mid-handshake.
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	msg := err.Error()
+	for _, substr := range []string{
+		"connection refused",
+		"no route to host",
+		"i/o timeout",
+		"EOF",
+		"reset by peer",
+	} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package sshx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+type fakeNetError struct{ timeout bool }
+
+func (e fakeNetError) Error() string   { return "fake net error" }
+func (e fakeNetError) Timeout() bool   { return e.timeout }
+func (e fakeNetError) Temporary() bool { return e.timeout }
+
+func TestIsTransient(t *testing.T) {
+	is := is.New(t)
+
+	is.True(isTransient(fakeNetError{timeout: true}))
+	is.True(!isTransient(fakeNetError{timeout: false}))
+	is.True(isTransient(errors.New("dial tcp: connection refused")))
+	is.True(isTransient(errors.New("dial tcp: no route to host")))
+	is.True(isTransient(fmt.Errorf("read: %w", errors.New("reset by peer"))))
+	is.True(!isTransient(errors.New("ssh: handshake failed: invalid password")))
+}
+
+func TestNextDelay(t *testing.T) {
+	is := is.New(t)
+	policy := RetryPolicy{Factor: 2, Max: 10 * time.Second}
+
+	is.Equal(nextDelay(time.Second, policy), 2*time.Second)
+	is.Equal(nextDelay(4*time.Second, policy), 8*time.Second)
+	// Capped at Max even though 8s*2 would otherwise be 16s.
+	is.Equal(nextDelay(8*time.Second, policy), 10*time.Second)
+}
+
+func TestJitteredDelay(t *testing.T) {
+	is := is.New(t)
+
+	// Zero jitter is a no-op.
+	is.Equal(jitteredDelay(time.Second, 0), time.Second)
+
+	// Otherwise the jittered delay never goes below the base delay or above
+	// delay*(1+jitter).
+	for i := 0; i < 100; i++ {
+		d := jitteredDelay(time.Second, 0.1)
+		is.True(d >= time.Second)
+		is.True(d <= time.Second+time.Millisecond*100)
+	}
+}
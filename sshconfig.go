@@ -0,0 +1,324 @@
+package sshx
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kevinburke/ssh_config"
+	"golang.org/x/crypto/ssh"
+)
+
+// Option customizes the *ssh.ClientConfig built by Configure. Resolve
+// returns a slice of Options capturing everything ~/.ssh/config knows
+// about a host; Dial applies them automatically, and callers can build
+// their own alongside explicit signers.
+type Option func(*options)
+
+type options struct {
+	signers               []ssh.Signer
+	identitiesOnly        bool
+	knownHostsFile        string
+	strictHostKeyChecking string
+	hostKeyPrompt         HostKeyPrompt
+	proxyJump             []string
+	connectTimeout        time.Duration
+	password              func() (string, error)
+	keyboardInteractive   ssh.KeyboardInteractiveChallenge
+}
+
+// WithSigner adds a public key signer to the auth methods tried during Dial.
+func WithSigner(signer ssh.Signer) Option {
+	return func(o *options) { o.signers = append(o.signers, signer) }
+}
+
+// WithIdentitiesOnly disables falling back to the ssh-agent, mirroring
+// OpenSSH's "IdentitiesOnly yes".
+func WithIdentitiesOnly() Option {
+	return func(o *options) { o.identitiesOnly = true }
+}
+
+// WithProxyJump records one or more [user@]host[:port] bastions to hop
+// through before reaching the final destination.
+func WithProxyJump(hops ...string) Option {
+	return func(o *options) { o.proxyJump = append(o.proxyJump, hops...) }
+}
+
+// WithConnectTimeout sets the dial timeout on the resulting *ssh.ClientConfig.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(o *options) { o.connectTimeout = d }
+}
+
+// applyOptions folds a set of Options into a single options value.
+func applyOptions(opts ...Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// signerOptions wraps each signer as a WithSigner Option.
+func signerOptions(signers []ssh.Signer) []Option {
+	opts := make([]Option, len(signers))
+	for i, signer := range signers {
+		opts[i] = WithSigner(signer)
+	}
+	return opts
+}
+
+// Resolve applies the user's OpenSSH client config (~/.ssh/config, falling
+// back to /etc/ssh/ssh_config) to alias. It returns the effective user and
+// host plus a set of Options that Dial needs to honor the rest: identity
+// signers loaded from every IdentityFile, IdentitiesOnly, ProxyJump hops,
+// and ConnectTimeout.
+//
+// Only Host blocks are evaluated. Match blocks are not: this package's
+// ssh_config dependency can't parse Match conditions at all, so rather than
+// fail the whole file over one unsupported block, Resolve skips their
+// bodies outright and never applies them. A config that puts HostName,
+// User, or any other directive Resolve reads behind a Match block won't
+// have it honored here, even though the equivalent Host block would be.
+//
+// If alias isn't mentioned in either file, Resolve returns it unchanged as
+// host with an empty user and no error, so it's always safe to call. A
+// non-nil error means the config itself couldn't be read or parsed. An
+// IdentityFile that can't be loaded, or a ConnectTimeout that isn't a whole
+// number of seconds, is skipped without an error.
+func Resolve(alias string) (user, host string, opts []Option, err error) {
+	cfgs, err := loadSSHConfigs()
+	if err != nil {
+		return "", alias, nil, err
+	}
+	l := &configLookup{cfgs: cfgs, alias: alias}
+
+	host = alias
+	if hostname := l.get("HostName"); hostname != "" {
+		host = hostname
+	}
+	user = l.get("User")
+	if port := l.get("Port"); port != "" && !strings.Contains(host, ":") {
+		host = net.JoinHostPort(host, port)
+	}
+
+	o := options{}
+	if strings.EqualFold(l.get("IdentitiesOnly"), "yes") {
+		o.identitiesOnly = true
+	}
+	if knownHosts := l.get("UserKnownHostsFile"); knownHosts != "" {
+		o.knownHostsFile = expandUser(knownHosts)
+	}
+	if strict := l.get("StrictHostKeyChecking"); strict != "" {
+		o.strictHostKeyChecking = strict
+	}
+	if proxyJump := l.get("ProxyJump"); proxyJump != "" && !strings.EqualFold(proxyJump, "none") {
+		for _, hop := range strings.Split(proxyJump, ",") {
+			o.proxyJump = append(o.proxyJump, strings.TrimSpace(hop))
+		}
+	}
+	if timeout := l.get("ConnectTimeout"); timeout != "" {
+		if seconds, err := strconv.Atoi(timeout); err == nil {
+			o.connectTimeout = time.Duration(seconds) * time.Second
+		}
+	}
+	identityFiles := l.getAll("IdentityFile")
+	if l.err != nil {
+		return "", alias, nil, l.err
+	}
+
+	for _, path := range identityFiles {
+		path = expandUser(expandTokens(path, host, user))
+		signer, err := LoadIdentity(path, nil)
+		if err != nil {
+			// Best-effort: an identity we can't read shouldn't block the dial,
+			// the agent or an explicitly-passed signer may still work.
+			continue
+		}
+		o.signers = append(o.signers, signer)
+	}
+
+	opts = []Option{func(dst *options) {
+		dst.identitiesOnly = o.identitiesOnly
+		dst.knownHostsFile = o.knownHostsFile
+		dst.strictHostKeyChecking = o.strictHostKeyChecking
+		dst.proxyJump = append(dst.proxyJump, o.proxyJump...)
+		dst.connectTimeout = o.connectTimeout
+		dst.signers = append(dst.signers, o.signers...)
+	}}
+	return user, host, opts, nil
+}
+
+// sshConfigs holds the user's ~/.ssh/config and the system-wide
+// /etc/ssh/ssh_config, each already decoded. A lookup checks the user
+// config first, falling back to the system config only when the user
+// config has nothing to say about a key - matching ssh(1).
+type sshConfigs struct {
+	user   *ssh_config.Config
+	system *ssh_config.Config
+}
+
+func loadSSHConfigs() (*sshConfigs, error) {
+	var userPath string
+	if home, err := os.UserHomeDir(); err == nil {
+		userPath = filepath.Join(home, ".ssh", "config")
+	}
+
+	user, err := decodeSSHConfigFile(userPath)
+	if err != nil {
+		return nil, err
+	}
+	system, err := decodeSSHConfigFile("/etc/ssh/ssh_config")
+	if err != nil {
+		return nil, err
+	}
+	return &sshConfigs{user: user, system: system}, nil
+}
+
+// decodeSSHConfigFile reads and decodes path, returning a nil *Config (and
+// no error) if path is empty or doesn't exist.
+//
+// Match blocks are stripped before decoding: github.com/kevinburke/ssh_config
+// can't parse them, and its own Decode fails the entire file when one is
+// present, which would silently hide every Host directive in the file too.
+// Resolve doesn't evaluate Match conditions, but it must not let an
+// unsupported directive blind it to the rest of the file.
+func decodeSSHConfigFile(path string) (*ssh_config.Config, error) {
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ssh: could not read %s: %w", path, err)
+	}
+	cfg, err := ssh_config.Decode(strings.NewReader(stripMatchBlocks(string(raw))))
+	if err != nil {
+		return nil, fmt.Errorf("ssh: could not parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// stripMatchBlocks drops every Match block (and its directives) from an
+// ssh_config file, leaving Host blocks untouched.
+func stripMatchBlocks(config string) string {
+	var out []string
+	skipping := false
+	for _, line := range strings.Split(config, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			switch strings.ToLower(fields[0]) {
+			case "match":
+				skipping = true
+				continue
+			case "host":
+				skipping = false
+			}
+		}
+		if !skipping {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func (c *sshConfigs) get(alias, key string) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if c.user != nil {
+		val, err := c.user.Get(alias, key)
+		if err != nil {
+			return "", err
+		}
+		if val != "" {
+			return val, nil
+		}
+	}
+	if c.system != nil {
+		return c.system.Get(alias, key)
+	}
+	return "", nil
+}
+
+func (c *sshConfigs) getAll(alias, key string) ([]string, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.user != nil {
+		vals, err := c.user.GetAll(alias, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(vals) > 0 {
+			return vals, nil
+		}
+	}
+	if c.system != nil {
+		return c.system.GetAll(alias, key)
+	}
+	return nil, nil
+}
+
+// configLookup accumulates the first error encountered across a series of
+// get/getAll calls, so Resolve can read a batch of keys without checking an
+// error after every single one.
+type configLookup struct {
+	cfgs  *sshConfigs
+	alias string
+	err   error
+}
+
+func (l *configLookup) get(key string) string {
+	if l.err != nil {
+		return ""
+	}
+	val, err := l.cfgs.get(l.alias, key)
+	if err != nil {
+		l.err = err
+	}
+	return val
+}
+
+func (l *configLookup) getAll(key string) []string {
+	if l.err != nil {
+		return nil
+	}
+	vals, err := l.cfgs.getAll(l.alias, key)
+	if err != nil {
+		l.err = err
+	}
+	return vals
+}
+
+// expandTokens expands the subset of ssh_config's %-tokens that matter for
+// IdentityFile: %h (hostname), %p (port), %r (remote user), and %%.
+func expandTokens(path, host, user string) string {
+	hostname, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostname, port = h, p
+	}
+	replacer := strings.NewReplacer(
+		"%h", hostname,
+		"%p", port,
+		"%r", user,
+		"%%", "%",
+	)
+	return replacer.Replace(path)
+}
+
+// expandUser expands a leading "~/" to the current user's home directory.
+func expandUser(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,71 @@
+package sshx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/matthewmueller/sshx"
+)
+
+func TestResolveSkipsMatchBlocks(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	sshDir := filepath.Join(dir, ".ssh")
+	is.NoErr(os.MkdirAll(sshDir, 0700))
+
+	config := `
+Match host other
+  User nobody
+
+Host box
+  HostName box.example.com
+  User deploy
+`
+	is.NoErr(os.WriteFile(filepath.Join(sshDir, "config"), []byte(config), 0600))
+	t.Setenv("HOME", dir)
+
+	user, host, _, err := sshx.Resolve("box")
+	is.NoErr(err)
+	is.Equal(user, "deploy")
+	is.Equal(host, "box.example.com")
+}
+
+func TestResolveUnknownAlias(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	user, host, opts, err := sshx.Resolve("nowhere")
+	is.NoErr(err)
+	is.Equal(user, "")
+	is.Equal(host, "nowhere")
+	is.Equal(len(opts), 1)
+}
+
+func TestSplitResolvesHostNameAndPort(t *testing.T) {
+	is := is.New(t)
+
+	dir := t.TempDir()
+	sshDir := filepath.Join(dir, ".ssh")
+	is.NoErr(os.MkdirAll(sshDir, 0700))
+
+	config := `
+Host myserver
+  HostName 10.0.0.5
+  Port 2222
+  User deploy
+`
+	is.NoErr(os.WriteFile(filepath.Join(sshDir, "config"), []byte(config), 0600))
+	t.Setenv("HOME", dir)
+
+	// Split must return the host Resolve actually computed - HostName and
+	// Port included - not a reformatted copy of the alias it was given.
+	user, host, err := sshx.Split("myserver")
+	is.NoErr(err)
+	is.Equal(user, "deploy")
+	is.Equal(host, "10.0.0.5:2222")
+}
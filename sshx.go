@@ -17,16 +17,27 @@ import (
 	"golang.org/x/term"
 )
 
-// Split a user@host[:port] string into user and host.
+// Split parses a user@host[:port] string into user and host. If userHost
+// isn't in that form, it's instead treated as an ssh config alias: Resolve
+// is consulted for the User, HostName, and Port it maps to, falling back to
+// the current OS user, the same way ssh(1) does, when the config doesn't
+// specify one.
 func Split(userHost string) (user string, host string, err error) {
 	parts := strings.Split(userHost, "@")
 	if len(parts) != 2 {
-		// SSH uses the current user by default
+		host = userHost
+		if ruser, rhost, _, rerr := Resolve(userHost); rerr == nil {
+			user, host = ruser, rhost
+		}
+		host = formatHost(host)
+		if user != "" {
+			return user, host, nil
+		}
 		current, err := osuser.Current()
 		if err != nil {
 			return "", "", fmt.Errorf("ssh: invalid user@host[:port] %q", userHost)
 		}
-		return current.Username, userHost, nil
+		return current.Username, host, nil
 	}
 	user, host = parts[0], parts[1]
 	return user, formatHost(host), nil
@@ -34,26 +45,42 @@ func Split(userHost string) (user string, host string, err error) {
 
 // Configure creates a new *ClientConfig based on sensible defaults.
 // This method is fairly error-resistent and intended for advanced use cases.
-func Configure(user, host string, signers ...ssh.Signer) *ssh.ClientConfig {
+func Configure(user, host string, opts ...Option) *ssh.ClientConfig {
 	host = formatHost(host)
-	config := configure(user, host, signers...)
+	o := applyOptions(opts...)
 
-	// Add the agent auth method if available
-	if agent, err := loadAgent(); nil == err {
-		config.Auth = append(config.Auth, ssh.PublicKeysCallback(agent.Signers))
+	config := configure(user, host, o.knownHostsFile, o.strictHostKeyChecking, o.hostKeyPrompt, o.signers...)
+	if o.connectTimeout > 0 {
+		config.Timeout = o.connectTimeout
+	}
+
+	// Add the agent auth method if available, unless IdentitiesOnly was set
+	if !o.identitiesOnly {
+		if agent, err := loadAgent(); nil == err {
+			config.Auth = append(config.Auth, ssh.PublicKeysCallback(agent.Signers))
+		}
+	}
+
+	// ssh(1) tries keyboard-interactive, then password, after publickey —
+	// and only prompts for each once the server actually offers it.
+	if o.keyboardInteractive != nil {
+		config.Auth = append(config.Auth, ssh.KeyboardInteractive(o.keyboardInteractive))
+	}
+	if o.password != nil {
+		config.Auth = append(config.Auth, ssh.PasswordCallback(o.password))
 	}
 
 	return config
 }
 
-func configure(user, host string, signers ...ssh.Signer) *ssh.ClientConfig {
+func configure(user, host, knownHostsFile, strictHostKeyChecking string, hostKeyPrompt HostKeyPrompt, signers ...ssh.Signer) *ssh.ClientConfig {
 	// Create the client config
 	config := &ssh.ClientConfig{
 		User: user,
 	}
 
 	// Add the known hosts if available
-	if knownHosts, err := loadKnownHosts(); nil == err {
+	if knownHosts, err := loadKnownHosts(knownHostsFile, strictHostKeyChecking, hostKeyPrompt); nil == err {
 		config.HostKeyCallback = knownHosts.HostKeyCallback()
 		config.HostKeyAlgorithms = knownHosts.HostKeyAlgorithms(host)
 	} else {
@@ -68,11 +95,31 @@ func configure(user, host string, signers ...ssh.Signer) *ssh.ClientConfig {
 	return config
 }
 
-// Dial creates a new ssh.Client with sensible defaults
+// Dial creates a new ssh.Client with sensible defaults. If host is an alias
+// defined in the user's ssh config, its HostName, Port, User, IdentityFile,
+// ProxyJump, and related directives are resolved and applied automatically,
+// so Dial("", "myserver") works transparently.
 func Dial(user, host string, signers ...ssh.Signer) (*ssh.Client, error) {
+	opts := make([]Option, 0, len(signers))
+	if ruser, rhost, ropts, err := Resolve(host); err == nil {
+		if user == "" {
+			user = ruser
+		}
+		host = rhost
+		opts = append(opts, ropts...)
+	}
+	for _, signer := range signers {
+		opts = append(opts, WithSigner(signer))
+	}
+
+	o := applyOptions(opts...)
+	if len(o.proxyJump) > 0 {
+		return DialJump(user, host, o.proxyJump, o.signers...)
+	}
+
 	host = formatHost(host)
 	// Configure the ssh client
-	config := Configure(user, host, signers...)
+	config := Configure(user, host, opts...)
 	// Dial the ssh connection
 	return ssh.Dial("tcp", host, config)
 }
@@ -90,7 +137,7 @@ func DialEach(user, host string, signers ...ssh.Signer) (*ssh.Client, ssh.Signer
 	}
 	// Try each signer until we find one that works
 	for _, signer := range signers {
-		config := configure(user, host, signer)
+		config := configure(user, host, "", "", nil, signer)
 		if client, err := ssh.Dial("tcp", host, config); nil == err {
 			return client, signer, nil
 		}
@@ -114,7 +161,7 @@ func Test(user, host string, signers ...ssh.Signer) (ssh.Signer, error) {
 
 	// Try each signer until we find one that works
 	for _, signer := range signers {
-		config := configure(user, host, signer)
+		config := configure(user, host, "", "", nil, signer)
 		if client, err := ssh.Dial("tcp", host, config); nil == err {
 			client.Close()
 			return signer, nil
@@ -224,42 +271,69 @@ func formatCommand(dir string, args ...string) string {
 	return fmt.Sprintf("cd %s && exec $SHELL -c %q", dir, strings.Join(args, " "))
 }
 
-func loadKnownHosts() (knownhosts.HostKeyCallback, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+func loadKnownHosts(knownHostsPath, strictHostKeyChecking string, prompt HostKeyPrompt) (knownhosts.HostKeyCallback, error) {
+	if knownHostsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
 	}
-	knownHostsPath := filepath.Join(home, ".ssh", "known_hosts")
 	knownHostsDb, err := knownhosts.NewDB(knownHostsPath)
 	if err != nil {
 		return nil, fmt.Errorf("ssh: could not load known_hosts db: %w", err)
 	}
+	if strictHostKeyChecking == "" {
+		strictHostKeyChecking = defaultStrictHostKeyChecking()
+	}
 
-	// Create a custom permissive hostkey callback which still errors on hosts
-	// with changed keys, but allows unknown hosts and adds them to known_hosts
 	return knownhosts.HostKeyCallback(func(hostname string, remote net.Addr, key ssh.PublicKey) error {
 		innerCallback := knownHostsDb.HostKeyCallback()
-		if err := innerCallback(hostname, remote, key); err != nil {
-			// Any error other than unknown host is fatal
-			if !knownhosts.IsHostUnknown(err) {
-				return err
+		err := innerCallback(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+		// A host whose key changed is always fatal, strict mode or not: it's
+		// the one case that must never be silently accepted.
+		if !knownhosts.IsHostUnknown(err) {
+			return fmt.Errorf("ssh: %w\nWARNING: %s key fingerprint %s does not match known_hosts", err, key.Type(), ssh.FingerprintSHA256(key))
+		}
+
+		switch strictHostKeyChecking {
+		case "yes":
+			return fmt.Errorf("ssh: host key verification failed: %s is not in known_hosts and StrictHostKeyChecking is enabled", hostname)
+		case "ask":
+			if prompt == nil {
+				prompt = TerminalHostKeyPrompt
 			}
-			// TODO: we should prompt the user to accept the new host key, similar to the ssh command
-			//
-			// The authenticity of host 'xx.xx.xxx.xxx (xx.xx.xxx.xxx)' can't be established.
-			// ED25519 key fingerprint is SHA256:xxx.
-			// This key is not known by any other names.
-			// Are you sure you want to continue connecting (yes/no/[fingerprint])?
-			if file, err := os.OpenFile(knownHostsPath, os.O_APPEND|os.O_WRONLY, 0600); nil == err {
-				defer file.Close()
-				// Attempt to write the new host to known_hosts, but don't fail if it doesn't work
-				knownhosts.WriteKnownHost(file, hostname, remote, key)
+			accept, err := prompt(hostname, remote, key)
+			if err != nil {
+				return fmt.Errorf("ssh: host key prompt failed: %w", err)
 			}
+			if !accept {
+				return fmt.Errorf("ssh: host key verification failed: %s rejected by user", hostname)
+			}
+		}
+		// accept-new (and ask once accepted): persist the new key, but don't
+		// fail the dial if we can't.
+		if file, err := os.OpenFile(knownHostsPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600); nil == err {
+			defer file.Close()
+			knownhosts.WriteKnownHost(file, hostname, remote, key)
 		}
 		return nil
 	}), nil
 }
 
+// defaultStrictHostKeyChecking mirrors ssh(1): prompt interactively when
+// connected to a terminal, otherwise silently accept-and-remember new host
+// keys as sshx has always done.
+func defaultStrictHostKeyChecking() string {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return "ask"
+	}
+	return "accept-new"
+}
+
 // loadAgent returns an SSH agent client if available.
 func loadAgent() (agent.ExtendedAgent, error) {
 	unixSocket := os.Getenv("SSH_AUTH_SOCK")
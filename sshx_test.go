@@ -22,3 +22,14 @@ func TestSplitWithPort(t *testing.T) {
 	is.Equal(user, "user")
 	is.Equal(host, "host:1234")
 }
+
+func TestSplitNoUserFallsBackToCurrentUser(t *testing.T) {
+	is := is.New(t)
+	// DialJump builds its final hop as a bare host when no user was given
+	// (rather than "@host"), so that it falls back to the current OS user
+	// here, the way ssh(1) does, instead of Split parsing an explicit empty
+	// user out of the leading "@".
+	user, _, err := sshx.Split("host")
+	is.NoErr(err)
+	is.True(user != "")
+}